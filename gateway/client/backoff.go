@@ -0,0 +1,38 @@
+package client
+
+import (
+	"math"
+	"math/rand"
+	"time"
+)
+
+// Backoff computes exponential backoff durations with full jitter,
+// bounded between Min and Max.
+type Backoff struct {
+	Min time.Duration
+	Max time.Duration
+}
+
+// NewBackoff returns a Backoff bounded between min and max.
+func NewBackoff(min, max time.Duration) *Backoff {
+	return &Backoff{Min: min, Max: max}
+}
+
+// Duration returns a jittered backoff duration for the given zero-based
+// attempt number, doubling from Min and never exceeding Max. The result
+// is always at least Min: jitter is applied within [Min, ceiling), not
+// from zero, so Min is a real floor rather than just a growth base.
+func (b *Backoff) Duration(attempt int) time.Duration {
+	if attempt < 0 {
+		attempt = 0
+	}
+	ceiling := float64(b.Min) * math.Pow(2, float64(attempt))
+	if ceiling <= 0 || ceiling > float64(b.Max) {
+		ceiling = float64(b.Max)
+	}
+	if ceiling <= float64(b.Min) {
+		return b.Min
+	}
+	span := int64(ceiling) - int64(b.Min)
+	return b.Min + time.Duration(rand.Int63n(span))
+}
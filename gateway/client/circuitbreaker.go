@@ -0,0 +1,74 @@
+package client
+
+import (
+	"sync"
+	"time"
+)
+
+type breakerState int
+
+const (
+	breakerClosed breakerState = iota
+	breakerOpen
+	breakerHalfOpen
+)
+
+// CircuitBreaker trips per upstream endpoint after FailureThreshold
+// consecutive failures, rejecting calls until ResetTimeout has elapsed,
+// at which point a single trial call is allowed through to probe recovery.
+type CircuitBreaker struct {
+	FailureThreshold int
+	ResetTimeout     time.Duration
+
+	mu       sync.Mutex
+	state    breakerState
+	failures int
+	openedAt time.Time
+}
+
+// Allow reports whether a call may proceed. A half-open breaker allows
+// exactly one probing call through.
+func (b *CircuitBreaker) Allow() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	switch b.state {
+	case breakerOpen:
+		if time.Since(b.openedAt) < b.ResetTimeout {
+			return false
+		}
+		b.state = breakerHalfOpen
+		return true
+	case breakerHalfOpen:
+		return false
+	default:
+		return true
+	}
+}
+
+// RecordSuccess closes the breaker and resets its failure count.
+func (b *CircuitBreaker) RecordSuccess() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.state = breakerClosed
+	b.failures = 0
+}
+
+// RecordFailure counts a failed call, tripping the breaker open once
+// FailureThreshold is reached.
+func (b *CircuitBreaker) RecordFailure() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.state == breakerHalfOpen {
+		b.state = breakerOpen
+		b.openedAt = time.Now()
+		return
+	}
+
+	b.failures++
+	if b.failures >= b.FailureThreshold {
+		b.state = breakerOpen
+		b.openedAt = time.Now()
+	}
+}
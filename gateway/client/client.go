@@ -0,0 +1,206 @@
+// Package client wraps the generated scalellm gRPC stubs with a
+// resilient calling layer: exponential backoff with jitter, per-priority
+// retry budgets, and a circuit breaker per upstream endpoint.
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+
+	scalellm "github.com/vectorch-ai/scalellm/gateway/proto"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// ErrCircuitOpen is returned when an endpoint's circuit breaker is open
+// and a call is rejected without being attempted.
+var ErrCircuitOpen = errors.New("client: circuit breaker open")
+
+// Options configures a Client's resiliency behavior.
+type Options struct {
+	// Backoff bounds the delay between retry attempts. Defaults to 5s-4h.
+	Backoff *Backoff
+	// RetryBudget bounds attempts per request priority. Defaults to
+	// DefaultRetryBudget.
+	RetryBudget *RetryBudget
+	// FailureThreshold is consecutive failures before an endpoint's
+	// circuit breaker trips open. Defaults to 5.
+	FailureThreshold int
+	// ResetTimeout is how long a tripped breaker stays open before
+	// allowing a probing call through. Defaults to 30s.
+	ResetTimeout time.Duration
+}
+
+// DefaultOptions returns the repo's default resiliency configuration.
+func DefaultOptions() Options {
+	return Options{
+		Backoff:          NewBackoff(5*time.Second, 4*time.Hour),
+		RetryBudget:      DefaultRetryBudget(),
+		FailureThreshold: 5,
+		ResetTimeout:     30 * time.Second,
+	}
+}
+
+// Client resiliently drives calls against upstream scalellm gRPC
+// endpoints, threading the request's Priority into retry decisions.
+type Client struct {
+	opts     Options
+	breakers sync.Map // endpoint string -> *CircuitBreaker
+}
+
+// New builds a Client from opts, filling in defaults for any zero-valued
+// fields.
+func New(opts Options) *Client {
+	def := DefaultOptions()
+	if opts.Backoff == nil {
+		opts.Backoff = def.Backoff
+	}
+	if opts.RetryBudget == nil {
+		opts.RetryBudget = def.RetryBudget
+	}
+	if opts.FailureThreshold <= 0 {
+		opts.FailureThreshold = def.FailureThreshold
+	}
+	if opts.ResetTimeout <= 0 {
+		opts.ResetTimeout = def.ResetTimeout
+	}
+	return &Client{opts: opts}
+}
+
+func (c *Client) breaker(endpoint string) *CircuitBreaker {
+	if b, ok := c.breakers.Load(endpoint); ok {
+		return b.(*CircuitBreaker)
+	}
+	b, _ := c.breakers.LoadOrStore(endpoint, &CircuitBreaker{
+		FailureThreshold: c.opts.FailureThreshold,
+		ResetTimeout:     c.opts.ResetTimeout,
+	})
+	return b.(*CircuitBreaker)
+}
+
+// Call invokes fn against endpoint with retry, backoff, and
+// circuit-breaking, giving priority requests a retry budget proportional
+// to their urgency.
+func (c *Client) Call(ctx context.Context, endpoint string, priority scalellm.Priority, fn func(ctx context.Context) error) error {
+	breaker := c.breaker(endpoint)
+	maxAttempts := c.opts.RetryBudget.maxAttempts(priority)
+
+	var lastErr error
+	for attempt := 0; attempt < maxAttempts; attempt++ {
+		if !breaker.Allow() {
+			return fmt.Errorf("%s: %w", endpoint, ErrCircuitOpen)
+		}
+
+		err := fn(ctx)
+		if err == nil {
+			breaker.RecordSuccess()
+			return nil
+		}
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		breaker.RecordFailure()
+		lastErr = err
+
+		if !isRetriable(err) || attempt == maxAttempts-1 {
+			break
+		}
+		if !c.sleepBackoff(ctx, attempt) {
+			return ctx.Err()
+		}
+	}
+	return lastErr
+}
+
+// StreamOpener re-establishes a streaming completion call, given the
+// chunk_index the caller next expects. A value of 0 means no chunk has
+// been processed yet and the stream should start from the beginning.
+type StreamOpener[T any] func(ctx context.Context, fromChunkIndex uint64) (scalellm.ChunkReceiver[T], error)
+
+// StreamCall wraps a resumable streaming RPC with the same retry,
+// backoff, and circuit-breaking policy as Call. On a mid-stream
+// disconnect, it re-invokes open with the index immediately after the
+// last chunk_index reported by chunkIndex, so the stream resumes rather
+// than restarts and no chunk is delivered twice. onChunk is called for
+// every received chunk; returning false stops iteration.
+//
+// StreamCall is a package-level function rather than a Client method
+// because Go methods cannot carry their own type parameters.
+func StreamCall[T any](c *Client, ctx context.Context, endpoint string, priority scalellm.Priority, open StreamOpener[T], chunkIndex func(*T) uint64, onChunk func(*T) bool) error {
+	breaker := c.breaker(endpoint)
+	maxAttempts := c.opts.RetryBudget.maxAttempts(priority)
+
+	var nextIndex uint64
+	var lastErr error
+	for attempt := 0; attempt < maxAttempts; attempt++ {
+		if !breaker.Allow() {
+			return fmt.Errorf("%s: %w", endpoint, ErrCircuitOpen)
+		}
+
+		recv, err := open(ctx, nextIndex)
+		if err == nil {
+			err = drainStream(recv, chunkIndex, onChunk, &nextIndex)
+		}
+		if err == nil {
+			breaker.RecordSuccess()
+			return nil
+		}
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		breaker.RecordFailure()
+		lastErr = err
+
+		if !isRetriable(err) || attempt == maxAttempts-1 {
+			break
+		}
+		if !c.sleepBackoff(ctx, attempt) {
+			return ctx.Err()
+		}
+	}
+	return lastErr
+}
+
+func drainStream[T any](recv scalellm.ChunkReceiver[T], chunkIndex func(*T) uint64, onChunk func(*T) bool, nextIndex *uint64) error {
+	for {
+		chunk, err := recv.Recv()
+		if err != nil {
+			return ignoreEOF(err)
+		}
+		*nextIndex = chunkIndex(chunk) + 1
+		if !onChunk(chunk) {
+			return nil
+		}
+	}
+}
+
+func (c *Client) sleepBackoff(ctx context.Context, attempt int) bool {
+	select {
+	case <-ctx.Done():
+		return false
+	case <-time.After(c.opts.Backoff.Duration(attempt)):
+		return true
+	}
+}
+
+func ignoreEOF(err error) error {
+	if errors.Is(err, io.EOF) {
+		return nil
+	}
+	return err
+}
+
+// isRetriable reports whether err represents a transient failure worth
+// retrying, based on its gRPC status code.
+func isRetriable(err error) bool {
+	switch status.Code(err) {
+	case codes.Unavailable, codes.ResourceExhausted, codes.Aborted, codes.DeadlineExceeded:
+		return true
+	default:
+		return false
+	}
+}
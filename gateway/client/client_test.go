@@ -0,0 +1,144 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"io"
+	"testing"
+	"time"
+
+	scalellm "github.com/vectorch-ai/scalellm/gateway/proto"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// fakeChunk is a minimal stand-in for a generated streaming completion
+// chunk, carrying just enough to drive resume-from-chunk_index.
+type fakeChunk struct {
+	index uint64
+}
+
+// fakeReceiver yields a fixed sequence of chunks, failing with a
+// retriable error partway through the first run to simulate a mid-stream
+// disconnect.
+type fakeReceiver struct {
+	chunks  []fakeChunk
+	failAt  int // index into chunks at which Recv returns an error once, before yielding that chunk
+	failed  bool
+	nextIdx int
+}
+
+func (r *fakeReceiver) Recv() (*fakeChunk, error) {
+	if r.nextIdx >= len(r.chunks) {
+		return nil, io.EOF
+	}
+	if !r.failed && r.nextIdx == r.failAt {
+		r.failed = true
+		return nil, status.Error(codes.Unavailable, "disconnected")
+	}
+	c := r.chunks[r.nextIdx]
+	r.nextIdx++
+	return &c, nil
+}
+
+func TestStreamCallResumesFromLastChunkIndex(t *testing.T) {
+	all := []fakeChunk{{0}, {1}, {2}, {3}, {4}}
+
+	var opened []uint64
+	firstAttempt := true
+	open := func(ctx context.Context, fromChunkIndex uint64) (scalellm.ChunkReceiver[fakeChunk], error) {
+		opened = append(opened, fromChunkIndex)
+		var remaining []fakeChunk
+		for _, c := range all {
+			if c.index >= fromChunkIndex {
+				remaining = append(remaining, c)
+			}
+		}
+		failAt := -1
+		if firstAttempt {
+			// Only the first attempt simulates a disconnect, partway through.
+			firstAttempt = false
+			failAt = 2
+		}
+		return &fakeReceiver{chunks: remaining, failAt: failAt}, nil
+	}
+
+	var got []uint64
+	onChunk := func(c *fakeChunk) bool {
+		got = append(got, c.index)
+		return true
+	}
+
+	c := New(Options{
+		Backoff:     NewBackoff(time.Millisecond, 10*time.Millisecond),
+		RetryBudget: &RetryBudget{MaxAttempts: map[scalellm.Priority]int{scalellm.Priority_HIGH: 3}},
+	})
+
+	err := StreamCall[fakeChunk](c, context.Background(), "endpoint-a", scalellm.Priority_HIGH, open,
+		func(c *fakeChunk) uint64 { return c.index }, onChunk)
+	if err != nil {
+		t.Fatalf("StreamCall returned error: %v", err)
+	}
+
+	wantOpened := []uint64{0, 2}
+	if len(opened) != len(wantOpened) {
+		t.Fatalf("open called with %v, want %v", opened, wantOpened)
+	}
+	for i, v := range wantOpened {
+		if opened[i] != v {
+			t.Fatalf("open called with %v, want %v", opened, wantOpened)
+		}
+	}
+
+	wantChunks := []uint64{0, 1, 2, 3, 4}
+	if len(got) != len(wantChunks) {
+		t.Fatalf("received chunk indexes %v, want %v", got, wantChunks)
+	}
+	for i, v := range wantChunks {
+		if got[i] != v {
+			t.Fatalf("received chunk indexes %v, want %v", got, wantChunks)
+		}
+	}
+}
+
+func TestStreamCallGivesUpAfterRetryBudget(t *testing.T) {
+	open := func(ctx context.Context, fromChunkIndex uint64) (scalellm.ChunkReceiver[fakeChunk], error) {
+		return nil, status.Error(codes.Unavailable, "always down")
+	}
+
+	c := New(Options{
+		Backoff:     NewBackoff(time.Millisecond, 5*time.Millisecond),
+		RetryBudget: &RetryBudget{MaxAttempts: map[scalellm.Priority]int{scalellm.Priority_LOW: 2}},
+	})
+
+	attempts := 0
+	err := StreamCall[fakeChunk](c, context.Background(), "endpoint-b", scalellm.Priority_LOW, func(ctx context.Context, from uint64) (scalellm.ChunkReceiver[fakeChunk], error) {
+		attempts++
+		return open(ctx, from)
+	}, func(c *fakeChunk) uint64 { return c.index }, func(c *fakeChunk) bool { return true })
+
+	if err == nil {
+		t.Fatal("expected error after exhausting retry budget, got nil")
+	}
+	if attempts != 2 {
+		t.Fatalf("attempts = %d, want 2 (LOW's retry budget)", attempts)
+	}
+}
+
+func TestCallSkipsBreakerOnContextCancellation(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	c := New(DefaultOptions())
+	err := c.Call(ctx, "endpoint-c", scalellm.Priority_NORMAL, func(ctx context.Context) error {
+		return ctx.Err()
+	})
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("err = %v, want context.Canceled", err)
+	}
+
+	b := c.breaker("endpoint-c")
+	if !b.Allow() {
+		t.Fatal("breaker tripped open from a caller-driven context cancellation")
+	}
+}
@@ -0,0 +1,46 @@
+package client
+
+import scalellm "github.com/vectorch-ai/scalellm/gateway/proto"
+
+// Default per-priority retry attempt ceilings: HIGH keeps trying well
+// past a transient blip, LOW gives up almost immediately so it doesn't
+// waste capacity that HIGH/NORMAL traffic needs under pressure.
+const (
+	DefaultHighMaxAttempts   = 8
+	DefaultNormalMaxAttempts = 4
+	DefaultLowMaxAttempts    = 1
+)
+
+// RetryBudget bounds how many attempts a request may consume, keyed by
+// its priority.
+type RetryBudget struct {
+	MaxAttempts map[scalellm.Priority]int
+}
+
+// DefaultRetryBudget returns the repo's default per-priority attempt
+// ceilings.
+func DefaultRetryBudget() *RetryBudget {
+	return &RetryBudget{
+		MaxAttempts: map[scalellm.Priority]int{
+			scalellm.Priority_HIGH:   DefaultHighMaxAttempts,
+			scalellm.Priority_NORMAL: DefaultNormalMaxAttempts,
+			scalellm.Priority_LOW:    DefaultLowMaxAttempts,
+		},
+	}
+}
+
+func (b *RetryBudget) maxAttempts(p scalellm.Priority) int {
+	if n, ok := b.MaxAttempts[normalize(p)]; ok && n > 0 {
+		return n
+	}
+	return DefaultNormalMaxAttempts
+}
+
+// normalize resolves DEFAULT to NORMAL; every other priority is returned
+// unchanged.
+func normalize(p scalellm.Priority) scalellm.Priority {
+	if p == scalellm.Priority_DEFAULT {
+		return scalellm.Priority_NORMAL
+	}
+	return p
+}
@@ -0,0 +1,141 @@
+package scalellm
+
+import (
+	"errors"
+	"io"
+	"iter"
+	"time"
+)
+
+// ThroughputSample is a live snapshot of streaming generation throughput,
+// emitted by UsageAggregator as each chunk carrying a ChunkUsage delta
+// arrives.
+type ThroughputSample struct {
+	ChunkIndex       uint64
+	TokensPerSecond  float64
+	PromptTokens     int32
+	CompletionTokens int32
+}
+
+// UsageExtractor pulls the optional ChunkUsage delta out of a streamed
+// response chunk of type T. Callers supply this because the concrete
+// streaming chunk type is defined by each RPC's own generated stubs, not
+// by this package.
+type UsageExtractor[T any] func(chunk *T) *ChunkUsage
+
+// ChunkReceiver is satisfied by any generated streaming client's Recv
+// method, e.g. the stub returned by a server-streaming completion RPC.
+type ChunkReceiver[T any] interface {
+	Recv() (*T, error)
+}
+
+// UsageAggregator wraps a streaming gRPC response and accumulates
+// ChunkUsage deltas into a running Usage tally and live throughput
+// samples, for RPCs where the caller set StreamOptions.IncrementalUsage.
+type UsageAggregator[T any] struct {
+	recv    ChunkReceiver[T]
+	extract UsageExtractor[T]
+	samples chan ThroughputSample
+
+	promptTokens     int32
+	completionTokens int32
+	lastErr          error
+}
+
+// NewUsageAggregator wraps recv, using extract to pull the ChunkUsage
+// delta (if any) out of each received chunk.
+func NewUsageAggregator[T any](recv ChunkReceiver[T], extract UsageExtractor[T]) *UsageAggregator[T] {
+	return &UsageAggregator[T]{
+		recv:    recv,
+		extract: extract,
+		samples: make(chan ThroughputSample, 16),
+	}
+}
+
+// Samples returns a channel of live throughput samples, at most one per
+// chunk that carries a ChunkUsage delta. Samples are sent without
+// blocking: when the channel's buffer of 16 is full, the sample is
+// dropped. The channel is closed when iteration over All ends, whether
+// by EOF, a stream error, or the caller stopping early.
+func (a *UsageAggregator[T]) Samples() <-chan ThroughputSample {
+	return a.samples
+}
+
+// All ranges over the underlying stream, yielding each received chunk
+// alongside the running Usage tally accumulated so far. Iteration stops
+// when the stream ends; callers should check Err afterward to
+// distinguish a clean EOF from a stream error.
+func (a *UsageAggregator[T]) All() iter.Seq2[*T, *Usage] {
+	return func(yield func(*T, *Usage) bool) {
+		defer close(a.samples)
+		start := time.Now()
+		for {
+			chunk, err := a.recv.Recv()
+			if err != nil {
+				a.lastErr = ignoreEOF(err)
+				return
+			}
+			a.accumulate(chunk, start)
+			if !yield(chunk, a.usage()) {
+				return
+			}
+		}
+	}
+}
+
+func (a *UsageAggregator[T]) accumulate(chunk *T, start time.Time) {
+	delta := a.extract(chunk)
+	if delta == nil {
+		return
+	}
+	a.promptTokens += delta.GetPromptTokens()
+	a.completionTokens += delta.GetCompletionTokens()
+
+	sample := ThroughputSample{
+		ChunkIndex:       delta.GetChunkIndex(),
+		TokensPerSecond:  delta.GetTokensPerSecond(),
+		PromptTokens:     a.promptTokens,
+		CompletionTokens: a.completionTokens,
+	}
+	if sample.TokensPerSecond == 0 {
+		if elapsed := time.Since(start).Seconds(); elapsed > 0 {
+			sample.TokensPerSecond = float64(a.completionTokens) / elapsed
+		}
+	}
+	select {
+	case a.samples <- sample:
+	default:
+	}
+}
+
+// usage returns the running Usage tally, compatible with the final Usage
+// message consumers already expect from non-incremental streaming. Each
+// call returns an independent copy so earlier snapshots are unaffected by
+// later accumulation.
+func (a *UsageAggregator[T]) usage() *Usage {
+	prompt, completion := a.promptTokens, a.completionTokens
+	total := prompt + completion
+	return &Usage{
+		PromptTokens:     &prompt,
+		CompletionTokens: &completion,
+		TotalTokens:      &total,
+	}
+}
+
+// Final returns the accumulated Usage message after the stream has ended.
+func (a *UsageAggregator[T]) Final() *Usage {
+	return a.usage()
+}
+
+// Err returns the error that ended iteration, or nil if the stream ended
+// cleanly.
+func (a *UsageAggregator[T]) Err() error {
+	return a.lastErr
+}
+
+func ignoreEOF(err error) error {
+	if errors.Is(err, io.EOF) {
+		return nil
+	}
+	return err
+}
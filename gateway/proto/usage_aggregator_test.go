@@ -0,0 +1,150 @@
+package scalellm
+
+import (
+	"errors"
+	"io"
+	"testing"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+type fakeAggChunk struct {
+	usage *ChunkUsage
+}
+
+type fakeAggReceiver struct {
+	chunks []fakeAggChunk
+	idx    int
+	endErr error // returned after chunks are exhausted; io.EOF if nil
+}
+
+func (r *fakeAggReceiver) Recv() (*fakeAggChunk, error) {
+	if r.idx >= len(r.chunks) {
+		if r.endErr != nil {
+			return nil, r.endErr
+		}
+		return nil, io.EOF
+	}
+	c := r.chunks[r.idx]
+	r.idx++
+	return &c, nil
+}
+
+func i32(v int32) *int32 { return &v }
+
+func chunkUsage(prompt, completion int32, index uint64) *ChunkUsage {
+	return &ChunkUsage{
+		PromptTokens:     i32(prompt),
+		CompletionTokens: i32(completion),
+		ChunkIndex:       index,
+	}
+}
+
+func TestUsageAggregatorAccumulatesAcrossChunks(t *testing.T) {
+	recv := &fakeAggReceiver{chunks: []fakeAggChunk{
+		{usage: chunkUsage(10, 1, 0)},
+		{usage: nil}, // a chunk with no usage delta should not affect the tally
+		{usage: chunkUsage(0, 2, 1)},
+		{usage: chunkUsage(0, 3, 2)},
+	}}
+	agg := NewUsageAggregator(recv, func(c *fakeAggChunk) *ChunkUsage { return c.usage })
+
+	var lastUsage *Usage
+	count := 0
+	for _, usage := range agg.All() {
+		count++
+		lastUsage = usage
+	}
+
+	if count != 4 {
+		t.Fatalf("iterated %d chunks, want 4", count)
+	}
+	if agg.Err() != nil {
+		t.Fatalf("Err() = %v, want nil after clean EOF", agg.Err())
+	}
+	if got := lastUsage.GetPromptTokens(); got != 10 {
+		t.Fatalf("final PromptTokens = %d, want 10", got)
+	}
+	if got := lastUsage.GetCompletionTokens(); got != 6 {
+		t.Fatalf("final CompletionTokens = %d, want 6", got)
+	}
+	if got := lastUsage.GetTotalTokens(); got != 16 {
+		t.Fatalf("final TotalTokens = %d, want 16", got)
+	}
+}
+
+func TestUsageAggregatorFinalMatchesLastYielded(t *testing.T) {
+	recv := &fakeAggReceiver{chunks: []fakeAggChunk{
+		{usage: chunkUsage(5, 5, 0)},
+	}}
+	agg := NewUsageAggregator(recv, func(c *fakeAggChunk) *ChunkUsage { return c.usage })
+
+	for range agg.All() {
+	}
+
+	final := agg.Final()
+	if final.GetPromptTokens() != 5 || final.GetCompletionTokens() != 5 {
+		t.Fatalf("Final() = %+v, want prompt=5 completion=5", final)
+	}
+}
+
+func TestUsageAggregatorPreservesEarlierSnapshots(t *testing.T) {
+	recv := &fakeAggReceiver{chunks: []fakeAggChunk{
+		{usage: chunkUsage(1, 1, 0)},
+		{usage: chunkUsage(1, 1, 1)},
+	}}
+	agg := NewUsageAggregator(recv, func(c *fakeAggChunk) *ChunkUsage { return c.usage })
+
+	var snapshots []*Usage
+	for _, usage := range agg.All() {
+		snapshots = append(snapshots, usage)
+	}
+
+	if len(snapshots) != 2 {
+		t.Fatalf("got %d snapshots, want 2", len(snapshots))
+	}
+	if snapshots[0].GetCompletionTokens() != 1 {
+		t.Fatalf("first snapshot CompletionTokens = %d, want 1 (must not mutate after later accumulation)", snapshots[0].GetCompletionTokens())
+	}
+	if snapshots[1].GetCompletionTokens() != 2 {
+		t.Fatalf("second snapshot CompletionTokens = %d, want 2", snapshots[1].GetCompletionTokens())
+	}
+}
+
+func TestUsageAggregatorReportsStreamError(t *testing.T) {
+	wantErr := status.Error(codes.Unavailable, "disconnected")
+	recv := &fakeAggReceiver{
+		chunks: []fakeAggChunk{{usage: chunkUsage(1, 1, 0)}},
+		endErr: wantErr,
+	}
+	agg := NewUsageAggregator(recv, func(c *fakeAggChunk) *ChunkUsage { return c.usage })
+
+	for range agg.All() {
+	}
+
+	if !errors.Is(agg.Err(), wantErr) {
+		t.Fatalf("Err() = %v, want %v", agg.Err(), wantErr)
+	}
+}
+
+func TestUsageAggregatorStopsEarlyOnFalseYield(t *testing.T) {
+	recv := &fakeAggReceiver{chunks: []fakeAggChunk{
+		{usage: chunkUsage(1, 1, 0)},
+		{usage: chunkUsage(1, 1, 1)},
+		{usage: chunkUsage(1, 1, 2)},
+	}}
+	agg := NewUsageAggregator(recv, func(c *fakeAggChunk) *ChunkUsage { return c.usage })
+
+	count := 0
+	for range agg.All() {
+		count++
+		if count == 1 {
+			break
+		}
+	}
+
+	if count != 1 {
+		t.Fatalf("iterated %d chunks, want 1 (range-over-func break should stop iteration)", count)
+	}
+}
@@ -0,0 +1,63 @@
+package scheduler
+
+import (
+	"sync"
+	"time"
+
+	scalellm "github.com/vectorch-ai/scalellm/gateway/proto"
+)
+
+// TierMetrics holds a point-in-time view of one priority tier's
+// scheduling state.
+type TierMetrics struct {
+	QueueDepth      int
+	LastWait        time.Duration
+	PreemptionCount int64
+}
+
+// Snapshot is a Metrics read taken at a single instant, keyed by
+// priority tier.
+type Snapshot map[scalellm.Priority]TierMetrics
+
+// Metrics tracks queue depth, dequeue wait time, and preemption counts
+// per priority tier for a PriorityScheduler.
+type Metrics struct {
+	mu   sync.Mutex
+	data map[scalellm.Priority]*TierMetrics
+}
+
+func newMetrics(tiers []scalellm.Priority) *Metrics {
+	m := &Metrics{data: make(map[scalellm.Priority]*TierMetrics, len(tiers))}
+	for _, t := range tiers {
+		m.data[t] = &TierMetrics{}
+	}
+	return m
+}
+
+func (m *Metrics) setQueueDepth(p scalellm.Priority, depth int) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.data[p].QueueDepth = depth
+}
+
+func (m *Metrics) observeWait(p scalellm.Priority, wait time.Duration) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.data[p].LastWait = wait
+}
+
+func (m *Metrics) incPreemptions(p scalellm.Priority) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.data[p].PreemptionCount++
+}
+
+func (m *Metrics) snapshot() Snapshot {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	out := make(Snapshot, len(m.data))
+	for p, v := range m.data {
+		out[p] = *v
+	}
+	return out
+}
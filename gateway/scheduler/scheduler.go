@@ -0,0 +1,392 @@
+// Package scheduler implements priority-aware admission control and
+// weighted-fair scheduling for inference requests flowing through the
+// gRPC gateway.
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	scalellm "github.com/vectorch-ai/scalellm/gateway/proto"
+)
+
+// ErrQueueFull is returned by Submit when the target priority's queue is
+// already at its configured depth.
+var ErrQueueFull = errors.New("scheduler: priority queue is full")
+
+// ErrRateLimited is returned by Submit when the target priority's
+// admission token bucket has no tokens available. Unlike ErrQueueFull,
+// this is transient backpressure: the caller should retry shortly rather
+// than treat it as capacity exhaustion.
+var ErrRateLimited = errors.New("scheduler: admission rejected by rate limiter")
+
+// ErrClosed is returned when the scheduler has been shut down.
+var ErrClosed = errors.New("scheduler: closed")
+
+// SaturationChecker reports whether the inference backend's KV-cache is
+// saturated. When it returns true, an arriving HIGH request is allowed to
+// preempt LOW-priority in-flight generations.
+type SaturationChecker func() bool
+
+// SchedulerOptions configures a PriorityScheduler.
+type SchedulerOptions struct {
+	// QueueDepths bounds how many pending requests may wait per priority
+	// tier before Submit starts returning ErrQueueFull. Priorities absent
+	// from the map fall back to DefaultQueueDepth.
+	QueueDepths map[scalellm.Priority]int
+	// Weights controls the share of dequeues each tier receives under
+	// weighted-fair scheduling. Priorities absent from the map fall back
+	// to DefaultWeight. Weights are relative, not percentages.
+	Weights map[scalellm.Priority]int
+	// TokensPerSecond and BurstSize configure the per-priority token-bucket
+	// admission limiter. Priorities absent from either map fall back to
+	// DefaultTokensPerSecond / DefaultBurstSize.
+	TokensPerSecond map[scalellm.Priority]float64
+	BurstSize       map[scalellm.Priority]int
+	// MaxPreemptionsPerSecond caps how often a HIGH-priority arrival may
+	// preempt a LOW in-flight generation, protecting LOW traffic from being
+	// starved entirely during sustained bursts.
+	MaxPreemptionsPerSecond float64
+	// AgingInterval is how long a request may sit in a queue below HIGH
+	// before it is promoted one priority level, so LOW requests eventually
+	// make progress under continuous HIGH/NORMAL pressure. Zero disables
+	// aging.
+	AgingInterval time.Duration
+	// IsSaturated reports current KV-cache pressure. If nil, preemption is
+	// never triggered.
+	IsSaturated SaturationChecker
+}
+
+const (
+	// DefaultQueueDepth is used for priorities not present in QueueDepths.
+	DefaultQueueDepth = 256
+	// DefaultWeight is used for priorities not present in Weights.
+	DefaultWeight = 1
+	// DefaultTokensPerSecond is used for priorities not present in
+	// TokensPerSecond.
+	DefaultTokensPerSecond = 50
+	// DefaultBurstSize is used for priorities not present in BurstSize.
+	DefaultBurstSize = 50
+)
+
+// priorityTiers is the fixed dequeue order used to build weighted-fair
+// rounds; DEFAULT never appears here because it is always normalized to
+// NORMAL before it reaches a queue.
+var priorityTiers = []scalellm.Priority{
+	scalellm.Priority_HIGH,
+	scalellm.Priority_NORMAL,
+	scalellm.Priority_LOW,
+}
+
+// normalize resolves DEFAULT to NORMAL; every other priority is returned
+// unchanged.
+func normalize(p scalellm.Priority) scalellm.Priority {
+	if p == scalellm.Priority_DEFAULT {
+		return scalellm.Priority_NORMAL
+	}
+	return p
+}
+
+// Request is a unit of scheduling work. Callers construct one per inbound
+// generation request and pass it to Submit.
+type Request struct {
+	// Priority is the caller-declared priority. Submit resolves DEFAULT to
+	// NORMAL, and aging may later raise it while the request is queued.
+	Priority scalellm.Priority
+	// Run performs the actual inference call. It receives a context that
+	// is canceled if the request is preempted while in flight; queued
+	// requests are never preempted.
+	Run func(ctx context.Context) error
+
+	enqueuedAt time.Time
+	cancel     context.CancelFunc
+}
+
+// PriorityScheduler sits between the gRPC handlers and the inference
+// backend. It admits requests through a per-priority token bucket, holds
+// them in per-priority FIFO queues, dispatches them in weighted-fair
+// order, and preempts LOW-priority in-flight generations to make room for
+// HIGH-priority arrivals when the backend reports saturation.
+type PriorityScheduler struct {
+	opts SchedulerOptions
+
+	mu       sync.Mutex
+	queues   map[scalellm.Priority][]*Request
+	inFlight map[scalellm.Priority]map[*Request]struct{}
+	buckets  map[scalellm.Priority]*tokenBucket
+	closed   bool
+	notify   chan struct{}
+
+	preemptions *rateLimiter
+	metrics     *Metrics
+
+	dispatchOnce sync.Once
+	stop         chan struct{}
+	wg           sync.WaitGroup
+}
+
+// NewPriorityScheduler builds a PriorityScheduler from opts and, when
+// opts.AgingInterval is positive, starts its background aging loop. The
+// dispatch loop is started by the first Submit. Callers must call Close
+// when done.
+func NewPriorityScheduler(opts SchedulerOptions) *PriorityScheduler {
+	s := &PriorityScheduler{
+		opts:        opts,
+		queues:      make(map[scalellm.Priority][]*Request, len(priorityTiers)),
+		inFlight:    make(map[scalellm.Priority]map[*Request]struct{}, len(priorityTiers)),
+		buckets:     make(map[scalellm.Priority]*tokenBucket, len(priorityTiers)),
+		notify:      make(chan struct{}, 1),
+		preemptions: newRateLimiter(opts.MaxPreemptionsPerSecond),
+		metrics:     newMetrics(priorityTiers),
+		stop:        make(chan struct{}),
+	}
+	for _, p := range priorityTiers {
+		s.queues[p] = nil
+		s.inFlight[p] = make(map[*Request]struct{})
+		s.buckets[p] = newTokenBucket(s.tokensPerSecond(p), s.burstSize(p))
+	}
+	if opts.AgingInterval > 0 {
+		s.wg.Add(1)
+		go s.ageLoop()
+	}
+	return s
+}
+
+func (s *PriorityScheduler) queueDepth(p scalellm.Priority) int {
+	if d, ok := s.opts.QueueDepths[p]; ok {
+		return d
+	}
+	return DefaultQueueDepth
+}
+
+func (s *PriorityScheduler) weight(p scalellm.Priority) int {
+	if w, ok := s.opts.Weights[p]; ok && w > 0 {
+		return w
+	}
+	return DefaultWeight
+}
+
+func (s *PriorityScheduler) tokensPerSecond(p scalellm.Priority) float64 {
+	if r, ok := s.opts.TokensPerSecond[p]; ok {
+		return r
+	}
+	return DefaultTokensPerSecond
+}
+
+func (s *PriorityScheduler) burstSize(p scalellm.Priority) int {
+	if b, ok := s.opts.BurstSize[p]; ok {
+		return b
+	}
+	return DefaultBurstSize
+}
+
+// Submit admits req for scheduling. It returns ErrRateLimited if the
+// request's priority tier has no admission tokens available right now,
+// ErrQueueFull if the tier's queue is already at its configured depth, and
+// ErrClosed if the scheduler has been shut down. Submit does not block;
+// Run is invoked asynchronously once the request is dispatched.
+func (s *PriorityScheduler) Submit(ctx context.Context, req *Request) error {
+	req.Priority = normalize(req.Priority)
+	bucket := s.buckets[req.Priority]
+	if bucket != nil && !bucket.Allow() {
+		return ErrRateLimited
+	}
+
+	s.mu.Lock()
+	if s.closed {
+		s.mu.Unlock()
+		return ErrClosed
+	}
+	if len(s.queues[req.Priority]) >= s.queueDepth(req.Priority) {
+		s.mu.Unlock()
+		return ErrQueueFull
+	}
+	req.enqueuedAt = time.Now()
+	s.queues[req.Priority] = append(s.queues[req.Priority], req)
+	s.metrics.setQueueDepth(req.Priority, len(s.queues[req.Priority]))
+	s.mu.Unlock()
+
+	s.maybePreempt(req.Priority)
+	s.wakeDispatcher()
+
+	s.dispatchOnce.Do(func() {
+		s.wg.Add(1)
+		go s.dispatchLoop()
+	})
+	return nil
+}
+
+// Close stops the scheduler's background loops. Requests already
+// dispatched continue running; queued requests are dropped.
+func (s *PriorityScheduler) Close() error {
+	s.mu.Lock()
+	if s.closed {
+		s.mu.Unlock()
+		return nil
+	}
+	s.closed = true
+	s.mu.Unlock()
+	close(s.stop)
+	s.wg.Wait()
+	return nil
+}
+
+// Metrics returns a point-in-time snapshot of per-tier scheduling metrics.
+func (s *PriorityScheduler) Metrics() Snapshot {
+	return s.metrics.snapshot()
+}
+
+func (s *PriorityScheduler) wakeDispatcher() {
+	select {
+	case s.notify <- struct{}{}:
+	default:
+	}
+}
+
+// dispatchLoop repeatedly picks the next request in weighted-fair order
+// and runs it in its own goroutine.
+func (s *PriorityScheduler) dispatchLoop() {
+	defer s.wg.Done()
+	rounds := newWeightedRound(s)
+	for {
+		select {
+		case <-s.stop:
+			return
+		case <-s.notify:
+		}
+		for {
+			req, ctx, priority, ok := s.dequeueNext(rounds)
+			if !ok {
+				break
+			}
+			s.runRequest(req, ctx, priority)
+		}
+	}
+}
+
+// dequeueNext advances the weighted-fair round until it finds a
+// non-empty queue or exhausts a full round with nothing to dispatch. The
+// returned request's cancel func is set here, under s.mu, before the
+// request is published into inFlight, so a concurrent maybePreempt can
+// never observe a request in inFlight with a nil or not-yet-visible
+// cancel func.
+func (s *PriorityScheduler) dequeueNext(r *weightedRound) (*Request, context.Context, scalellm.Priority, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for i := 0; i < r.len(); i++ {
+		p := r.next()
+		q := s.queues[p]
+		if len(q) == 0 {
+			continue
+		}
+		req := q[0]
+		s.queues[p] = q[1:]
+		s.metrics.setQueueDepth(p, len(s.queues[p]))
+		s.metrics.observeWait(p, time.Since(req.enqueuedAt))
+		ctx, cancel := context.WithCancel(context.Background())
+		req.cancel = cancel
+		s.inFlight[p][req] = struct{}{}
+		return req, ctx, p, true
+	}
+	return nil, nil, 0, false
+}
+
+func (s *PriorityScheduler) runRequest(req *Request, ctx context.Context, priority scalellm.Priority) {
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+		defer req.cancel()
+		defer s.removeInFlight(priority, req)
+		_ = req.Run(ctx)
+	}()
+}
+
+func (s *PriorityScheduler) removeInFlight(priority scalellm.Priority, req *Request) {
+	s.mu.Lock()
+	delete(s.inFlight[priority], req)
+	s.mu.Unlock()
+}
+
+// maybePreempt cancels one LOW in-flight request when a HIGH request
+// arrives and the backend reports saturation, subject to the configured
+// preemption rate limit.
+func (s *PriorityScheduler) maybePreempt(arriving scalellm.Priority) {
+	if arriving != scalellm.Priority_HIGH || s.opts.IsSaturated == nil {
+		return
+	}
+	if !s.opts.IsSaturated() {
+		return
+	}
+	if !s.preemptions.Allow() {
+		return
+	}
+
+	s.mu.Lock()
+	var victim *Request
+	for r := range s.inFlight[scalellm.Priority_LOW] {
+		victim = r
+		break
+	}
+	s.mu.Unlock()
+
+	if victim != nil && victim.cancel != nil {
+		victim.cancel()
+		s.metrics.incPreemptions(scalellm.Priority_LOW)
+	}
+}
+
+// ageLoop periodically promotes the oldest LOW/NORMAL queued requests by
+// one priority tier so they eventually make progress under sustained
+// higher-priority load.
+func (s *PriorityScheduler) ageLoop() {
+	defer s.wg.Done()
+	ticker := time.NewTicker(s.opts.AgingInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-s.stop:
+			return
+		case <-ticker.C:
+			s.promoteAged()
+		}
+	}
+}
+
+// promoteAged promotes aged requests by exactly one priority tier per
+// tick. NORMAL->HIGH runs before LOW->NORMAL so a request promoted from
+// LOW to NORMAL in this tick is never reconsidered for NORMAL->HIGH until
+// a later tick, regardless of how long it had already been waiting as a
+// LOW request.
+func (s *PriorityScheduler) promoteAged() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	now := time.Now()
+
+	s.promoteTier(scalellm.Priority_NORMAL, scalellm.Priority_HIGH, now)
+	s.promoteTier(scalellm.Priority_LOW, scalellm.Priority_NORMAL, now)
+
+	s.wakeDispatcher()
+}
+
+// promoteTier moves requests that have aged past AgingInterval from the
+// front of queues[from] to the back of queues[to], resetting their
+// enqueuedAt so the promoted tier gets its own full aging interval before
+// promoting again. Callers must hold s.mu.
+func (s *PriorityScheduler) promoteTier(from, to scalellm.Priority, now time.Time) {
+	pending := s.queues[from]
+	remaining := pending[:0]
+	for _, req := range pending {
+		if now.Sub(req.enqueuedAt) >= s.opts.AgingInterval {
+			req.Priority = to
+			req.enqueuedAt = now
+			s.queues[to] = append(s.queues[to], req)
+			continue
+		}
+		remaining = append(remaining, req)
+	}
+	s.queues[from] = remaining
+	s.metrics.setQueueDepth(from, len(s.queues[from]))
+	s.metrics.setQueueDepth(to, len(s.queues[to]))
+}
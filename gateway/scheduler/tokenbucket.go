@@ -0,0 +1,67 @@
+package scheduler
+
+import (
+	"sync"
+	"time"
+)
+
+// tokenBucket implements per-priority admission control: requests are
+// admitted as long as tokens are available, and tokens are replenished
+// continuously at ratePerSecond up to burst.
+type tokenBucket struct {
+	mu         sync.Mutex
+	ratePerSec float64
+	burst      float64
+	tokens     float64
+	lastRefill time.Time
+}
+
+func newTokenBucket(ratePerSecond float64, burst int) *tokenBucket {
+	return &tokenBucket{
+		ratePerSec: ratePerSecond,
+		burst:      float64(burst),
+		tokens:     float64(burst),
+		lastRefill: time.Now(),
+	}
+}
+
+// Allow reports whether a request may be admitted right now, consuming a
+// token if so.
+func (b *tokenBucket) Allow() bool {
+	if b.ratePerSec <= 0 {
+		return true
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	now := time.Now()
+	elapsed := now.Sub(b.lastRefill).Seconds()
+	b.lastRefill = now
+	b.tokens += elapsed * b.ratePerSec
+	if b.tokens > b.burst {
+		b.tokens = b.burst
+	}
+	if b.tokens < 1 {
+		return false
+	}
+	b.tokens--
+	return true
+}
+
+// rateLimiter is a minimal token bucket with burst 1, used to cap
+// preemptions per second regardless of priority tier.
+type rateLimiter struct {
+	bucket *tokenBucket
+}
+
+func newRateLimiter(perSecond float64) *rateLimiter {
+	if perSecond <= 0 {
+		perSecond = 1
+	}
+	return &rateLimiter{bucket: newTokenBucket(perSecond, 1)}
+}
+
+func (r *rateLimiter) Allow() bool {
+	return r.bucket.Allow()
+}
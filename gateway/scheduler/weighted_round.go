@@ -0,0 +1,34 @@
+package scheduler
+
+import scalellm "github.com/vectorch-ai/scalellm/gateway/proto"
+
+// weightedRound produces a repeating dequeue order where each priority
+// tier appears proportionally to its configured weight, implementing
+// weighted-fair scheduling across the fixed HIGH/NORMAL/LOW tiers.
+type weightedRound struct {
+	order []scalellm.Priority
+	pos   int
+}
+
+func newWeightedRound(s *PriorityScheduler) *weightedRound {
+	var order []scalellm.Priority
+	for _, p := range priorityTiers {
+		for i := 0; i < s.weight(p); i++ {
+			order = append(order, p)
+		}
+	}
+	if len(order) == 0 {
+		order = append(order, priorityTiers...)
+	}
+	return &weightedRound{order: order}
+}
+
+func (r *weightedRound) len() int {
+	return len(r.order)
+}
+
+func (r *weightedRound) next() scalellm.Priority {
+	p := r.order[r.pos]
+	r.pos = (r.pos + 1) % len(r.order)
+	return p
+}